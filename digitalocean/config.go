@@ -0,0 +1,16 @@
+package digitalocean
+
+import "github.com/digitalocean/godo"
+
+// Config bundles the configured godo client together with provider-level
+// settings that individual resources need access to. It is what gets
+// passed to resources and data sources as meta.
+type Config struct {
+	Client *godo.Client
+
+	// FirewallParallelism bounds how many AddRules/RemoveRules requests
+	// resourceDigitalOceanFirewallUpdate issues concurrently when
+	// reconciling a rule diff. Configured via the provider's
+	// firewall_parallelism setting.
+	FirewallParallelism int
+}
@@ -0,0 +1,208 @@
+package digitalocean
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/digitalocean/godo"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceDigitalOceanFirewall() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceDigitalOceanFirewallRead,
+
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			"name": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			"status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"created_at": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"droplet_ids": {
+				Type:     schema.TypeSet,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Computed: true,
+			},
+
+			"tags": {
+				Type:     schema.TypeSet,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Computed: true,
+			},
+
+			"inbound_rule": {
+				Type:     schema.TypeSet,
+				Computed: true,
+				Set:      resourceDigitalOceanFirewallInboundRuleHash,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"protocol": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"port_range": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"source_addresses": {
+							Type:     schema.TypeSet,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+							Computed: true,
+						},
+						"source_tags": {
+							Type:     schema.TypeSet,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+							Computed: true,
+						},
+						"source_droplet_ids": {
+							Type:     schema.TypeSet,
+							Elem:     &schema.Schema{Type: schema.TypeInt},
+							Computed: true,
+						},
+						"source_load_balancer_uids": {
+							Type:     schema.TypeSet,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+							Computed: true,
+						},
+					},
+				},
+			},
+
+			"outbound_rule": {
+				Type:     schema.TypeSet,
+				Computed: true,
+				Set:      resourceDigitalOceanFirewallOutboundRuleHash,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"protocol": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"port_range": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"destination_addresses": {
+							Type:     schema.TypeSet,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+							Computed: true,
+						},
+						"destination_tags": {
+							Type:     schema.TypeSet,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+							Computed: true,
+						},
+						"destination_droplet_ids": {
+							Type:     schema.TypeSet,
+							Elem:     &schema.Schema{Type: schema.TypeInt},
+							Computed: true,
+						},
+						"destination_load_balancer_uids": {
+							Type:     schema.TypeSet,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceDigitalOceanFirewallRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Config).Client
+
+	firewall, err := findFirewall(d, client)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(firewall.ID)
+	d.Set("name", firewall.Name)
+	d.Set("status", firewall.Status)
+	d.Set("created_at", firewall.Created)
+	d.Set("droplet_ids", firewall.DropletIDs)
+	d.Set("tags", firewall.Tags)
+
+	if err := d.Set("inbound_rule", flattenFirewallInboundRules(firewall.InboundRules)); err != nil {
+		return fmt.Errorf("[DEBUG] Error setting Firewall inbound_rule error: %#v", err)
+	}
+
+	if err := d.Set("outbound_rule", flattenFirewallOutboundRules(firewall.OutboundRules)); err != nil {
+		return fmt.Errorf("[DEBUG] Error setting Firewall outbound_rule error: %#v", err)
+	}
+
+	return nil
+}
+
+// findFirewall looks a firewall up by id when given, falling back to an
+// exact name match over every firewall on the account.
+func findFirewall(d *schema.ResourceData, client *godo.Client) (*godo.Firewall, error) {
+	if v, ok := d.GetOk("id"); ok {
+		firewall, _, err := client.Firewalls.Get(context.Background(), v.(string))
+		if err != nil {
+			return nil, fmt.Errorf("Error retrieving firewall: %s", err)
+		}
+		return firewall, nil
+	}
+
+	name, ok := d.GetOk("name")
+	if !ok {
+		return nil, fmt.Errorf("One of `id` or `name` must be set")
+	}
+
+	firewalls, err := listFirewalls(client)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range firewalls {
+		if firewalls[i].Name == name.(string) {
+			return &firewalls[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("No firewall found with name %q", name.(string))
+}
+
+func listFirewalls(client *godo.Client) ([]godo.Firewall, error) {
+	list := []godo.Firewall{}
+
+	opts := &godo.ListOptions{}
+	for {
+		firewalls, resp, err := client.Firewalls.List(context.Background(), opts)
+		if err != nil {
+			return nil, fmt.Errorf("Error retrieving firewalls: %s", err)
+		}
+
+		list = append(list, firewalls...)
+
+		if resp.Links == nil || resp.Links.IsLastPage() {
+			break
+		}
+
+		page, err := resp.Links.CurrentPage()
+		if err != nil {
+			return nil, err
+		}
+
+		opts.Page = page + 1
+	}
+
+	return list, nil
+}
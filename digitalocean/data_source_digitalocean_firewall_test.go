@@ -0,0 +1,46 @@
+package digitalocean
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccDigitalOceanFirewallDataSource_Basic(t *testing.T) {
+	name := fmt.Sprintf("firewall-%s", acctest.RandString(10))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDigitalOceanFirewallDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckDigitalOceanFirewallDataSourceConfigBasic(name),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.digitalocean_firewall.foobar", "name", name),
+					resource.TestCheckResourceAttr("data.digitalocean_firewall.foobar", "inbound_rule.#", "1"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckDigitalOceanFirewallDataSourceConfigBasic(name string) string {
+	return fmt.Sprintf(`
+resource "digitalocean_firewall" "foobar" {
+  name = "%s"
+
+  inbound_rule {
+    protocol         = "tcp"
+    port_range       = "22"
+    source_addresses = ["0.0.0.0/0", "::/0"]
+  }
+}
+
+data "digitalocean_firewall" "foobar" {
+  id = digitalocean_firewall.foobar.id
+}
+`, name)
+}
@@ -0,0 +1,150 @@
+package digitalocean
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/digitalocean/godo"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// dataSourceDigitalOceanFirewallsByDroplet wraps godo's ListByDroplet so
+// modules can discover which firewalls guard a given droplet, e.g. to
+// attach additional digitalocean_firewall_rule resources to them or to
+// audit exposure.
+func dataSourceDigitalOceanFirewallsByDroplet() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceDigitalOceanFirewallsByDropletRead,
+
+		Schema: map[string]*schema.Schema{
+			"droplet_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+			},
+
+			"firewalls": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"status": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"created_at": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"droplet_ids": {
+							Type:     schema.TypeSet,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+							Computed: true,
+						},
+						"tags": {
+							Type:     schema.TypeSet,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+							Computed: true,
+						},
+						"inbound_rule": {
+							Type:     schema.TypeSet,
+							Computed: true,
+							Set:      resourceDigitalOceanFirewallInboundRuleHash,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"protocol":                  {Type: schema.TypeString, Computed: true},
+									"port_range":                {Type: schema.TypeString, Computed: true},
+									"source_addresses":          {Type: schema.TypeSet, Elem: &schema.Schema{Type: schema.TypeString}, Computed: true},
+									"source_tags":               {Type: schema.TypeSet, Elem: &schema.Schema{Type: schema.TypeString}, Computed: true},
+									"source_droplet_ids":        {Type: schema.TypeSet, Elem: &schema.Schema{Type: schema.TypeInt}, Computed: true},
+									"source_load_balancer_uids": {Type: schema.TypeSet, Elem: &schema.Schema{Type: schema.TypeString}, Computed: true},
+								},
+							},
+						},
+						"outbound_rule": {
+							Type:     schema.TypeSet,
+							Computed: true,
+							Set:      resourceDigitalOceanFirewallOutboundRuleHash,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"protocol":                       {Type: schema.TypeString, Computed: true},
+									"port_range":                     {Type: schema.TypeString, Computed: true},
+									"destination_addresses":          {Type: schema.TypeSet, Elem: &schema.Schema{Type: schema.TypeString}, Computed: true},
+									"destination_tags":               {Type: schema.TypeSet, Elem: &schema.Schema{Type: schema.TypeString}, Computed: true},
+									"destination_droplet_ids":        {Type: schema.TypeSet, Elem: &schema.Schema{Type: schema.TypeInt}, Computed: true},
+									"destination_load_balancer_uids": {Type: schema.TypeSet, Elem: &schema.Schema{Type: schema.TypeString}, Computed: true},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceDigitalOceanFirewallsByDropletRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Config).Client
+	dropletID := d.Get("droplet_id").(int)
+
+	firewalls, err := listFirewallsByDroplet(client, dropletID)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("%d", dropletID))
+
+	flattened := make([]interface{}, 0, len(firewalls))
+	for _, firewall := range firewalls {
+		flattened = append(flattened, map[string]interface{}{
+			"id":            firewall.ID,
+			"name":          firewall.Name,
+			"status":        firewall.Status,
+			"created_at":    firewall.Created,
+			"droplet_ids":   firewall.DropletIDs,
+			"tags":          firewall.Tags,
+			"inbound_rule":  flattenFirewallInboundRules(firewall.InboundRules),
+			"outbound_rule": flattenFirewallOutboundRules(firewall.OutboundRules),
+		})
+	}
+
+	if err := d.Set("firewalls", flattened); err != nil {
+		return fmt.Errorf("[DEBUG] Error setting firewalls error: %#v", err)
+	}
+
+	return nil
+}
+
+func listFirewallsByDroplet(client *godo.Client, dropletID int) ([]godo.Firewall, error) {
+	list := []godo.Firewall{}
+
+	opts := &godo.ListOptions{}
+	for {
+		firewalls, resp, err := client.Firewalls.ListByDroplet(context.Background(), dropletID, opts)
+		if err != nil {
+			return nil, fmt.Errorf("Error retrieving firewalls for droplet %d: %s", dropletID, err)
+		}
+
+		list = append(list, firewalls...)
+
+		if resp.Links == nil || resp.Links.IsLastPage() {
+			break
+		}
+
+		page, err := resp.Links.CurrentPage()
+		if err != nil {
+			return nil, err
+		}
+
+		opts.Page = page + 1
+	}
+
+	return list, nil
+}
@@ -0,0 +1,55 @@
+package digitalocean
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccDigitalOceanFirewallsByDropletDataSource_Basic(t *testing.T) {
+	name := fmt.Sprintf("firewall-%s", acctest.RandString(10))
+	dropletName := fmt.Sprintf("droplet-%s", acctest.RandString(10))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDigitalOceanFirewallDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckDigitalOceanFirewallsByDropletDataSourceConfigBasic(dropletName, name),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.digitalocean_firewalls_by_droplet.foobar", "firewalls.#", "1"),
+					resource.TestCheckResourceAttr("data.digitalocean_firewalls_by_droplet.foobar", "firewalls.0.name", name),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckDigitalOceanFirewallsByDropletDataSourceConfigBasic(dropletName, firewallName string) string {
+	return fmt.Sprintf(`
+resource "digitalocean_droplet" "foobar" {
+  name   = "%s"
+  size   = "s-1vcpu-1gb"
+  image  = "ubuntu-20-04-x64"
+  region = "nyc3"
+}
+
+resource "digitalocean_firewall" "foobar" {
+  name        = "%s"
+  droplet_ids = [digitalocean_droplet.foobar.id]
+
+  inbound_rule {
+    protocol         = "tcp"
+    port_range       = "22"
+    source_addresses = ["0.0.0.0/0", "::/0"]
+  }
+}
+
+data "digitalocean_firewalls_by_droplet" "foobar" {
+  droplet_id = tolist(digitalocean_firewall.foobar.droplet_ids)[0]
+}
+`, dropletName, firewallName)
+}
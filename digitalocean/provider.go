@@ -0,0 +1,59 @@
+package digitalocean
+
+import (
+	"context"
+
+	"github.com/digitalocean/godo"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"golang.org/x/oauth2"
+)
+
+const defaultFirewallParallelism = 5
+
+// Provider returns the digitalocean Terraform provider.
+func Provider() terraform.ResourceProvider {
+	return &schema.Provider{
+		Schema: map[string]*schema.Schema{
+			"token": {
+				Type:        schema.TypeString,
+				Required:    true,
+				DefaultFunc: schema.EnvDefaultFunc("DIGITALOCEAN_TOKEN", nil),
+				Description: "The token key for API operations.",
+			},
+
+			"firewall_parallelism": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     defaultFirewallParallelism,
+				Description: "The number of concurrent AddRules/RemoveRules requests digitalocean_firewall issues when reconciling a rule diff on Update.",
+			},
+		},
+
+		ResourcesMap: map[string]*schema.Resource{
+			"digitalocean_firewall":            resourceDigitalOceanFirewall(),
+			"digitalocean_firewall_rule":       resourceDigitalOceanFirewallRule(),
+			"digitalocean_firewall_attachment": resourceDigitalOceanFirewallAttachment(),
+		},
+
+		DataSourcesMap: map[string]*schema.Resource{
+			"digitalocean_firewall":             dataSourceDigitalOceanFirewall(),
+			"digitalocean_firewalls_by_droplet": dataSourceDigitalOceanFirewallsByDroplet(),
+		},
+
+		ConfigureFunc: providerConfigure,
+	}
+}
+
+func providerConfigure(d *schema.ResourceData) (interface{}, error) {
+	tokenSrc := oauth2.StaticTokenSource(&oauth2.Token{
+		AccessToken: d.Get("token").(string),
+	})
+
+	client := godo.NewClient(oauth2.NewClient(context.Background(), tokenSrc))
+
+	return &Config{
+		Client:              client,
+		FirewallParallelism: d.Get("firewall_parallelism").(int),
+	}, nil
+}
@@ -5,21 +5,30 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/digitalocean/godo"
+	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/terraform/helper/hashcode"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 )
 
+// firewallRuleThrottle is a small pause each worker takes between requests,
+// to stay clear of DigitalOcean's API rate limit when reconciling a large
+// firewall.
+const firewallRuleThrottle = 250 * time.Millisecond
+
 func resourceDigitalOceanFirewall() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceDigitalOceanFirewallCreate,
 		Read:   resourceDigitalOceanFirewallRead,
 		Update: resourceDigitalOceanFirewallUpdate,
 		Delete: resourceDigitalOceanFirewallDelete,
-		Exists: resourceDigitalOceanFirewallExists,
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
@@ -57,34 +66,65 @@ func resourceDigitalOceanFirewall() *schema.Resource {
 			},
 
 			"name": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.NoZeroValues,
+			},
+
+			// manage_rules controls whether this resource owns the
+			// firewall's full rule set. Leave it at the default of true
+			// to declare inbound_rule/outbound_rule here; set it to
+			// false to manage name/droplet/tag membership only and let
+			// digitalocean_firewall_rule resources attach rules
+			// incrementally without fighting this resource on refresh.
+			"manage_rules": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+
+			// manage_membership controls whether this resource owns the
+			// firewall's droplet/tag membership. Leave it at the default
+			// of true to declare droplet_ids/tags here; set it to false
+			// to manage the rule policy only and let
+			// digitalocean_firewall_attachment resources attach droplets
+			// and tags incrementally without fighting this resource on
+			// refresh.
+			"manage_membership": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
 			},
 
 			"droplet_ids": {
-				Type:     schema.TypeList,
+				Type:     schema.TypeSet,
 				Elem:     &schema.Schema{Type: schema.TypeString},
 				Optional: true,
 			},
 
 			"tags": {
-				Type:     schema.TypeList,
+				Type:     schema.TypeSet,
 				Elem:     &schema.Schema{Type: schema.TypeString},
 				Optional: true,
 			},
 
 			"inbound_rule": {
-				Type:     schema.TypeList,
+				Type:     schema.TypeSet,
 				Optional: true,
+				Set:      resourceDigitalOceanFirewallInboundRuleHash,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"protocol": {
 							Type:     schema.TypeString,
 							Optional: true,
+							ValidateFunc: validation.StringInSlice([]string{
+								"tcp", "udp", "icmp",
+							}, false),
 						},
 						"port_range": {
-							Type:     schema.TypeString,
-							Optional: true,
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateFirewallPortRange,
 							DiffSuppressFunc: func(k, oldV, newV string, d *schema.ResourceData) bool {
 								if oldV == "0" && newV == "all" {
 									return true
@@ -93,22 +133,22 @@ func resourceDigitalOceanFirewall() *schema.Resource {
 							},
 						},
 						"source_addresses": {
-							Type:     schema.TypeList,
+							Type:     schema.TypeSet,
 							Elem:     &schema.Schema{Type: schema.TypeString},
 							Optional: true,
 						},
 						"source_tags": {
-							Type:     schema.TypeList,
+							Type:     schema.TypeSet,
 							Elem:     &schema.Schema{Type: schema.TypeString},
 							Optional: true,
 						},
 						"source_droplet_ids": {
-							Type:     schema.TypeList,
+							Type:     schema.TypeSet,
 							Elem:     &schema.Schema{Type: schema.TypeInt},
 							Optional: true,
 						},
 						"source_load_balancer_uids": {
-							Type:     schema.TypeList,
+							Type:     schema.TypeSet,
 							Elem:     &schema.Schema{Type: schema.TypeString},
 							Optional: true,
 						},
@@ -117,17 +157,22 @@ func resourceDigitalOceanFirewall() *schema.Resource {
 			},
 
 			"outbound_rule": {
-				Type:     schema.TypeList,
+				Type:     schema.TypeSet,
 				Optional: true,
+				Set:      resourceDigitalOceanFirewallOutboundRuleHash,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"protocol": {
 							Type:     schema.TypeString,
 							Optional: true,
+							ValidateFunc: validation.StringInSlice([]string{
+								"tcp", "udp", "icmp",
+							}, false),
 						},
 						"port_range": {
-							Type:     schema.TypeString,
-							Optional: true,
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateFirewallPortRange,
 							DiffSuppressFunc: func(k, oldV, newV string, d *schema.ResourceData) bool {
 								if oldV == "0" && newV == "all" {
 									return true
@@ -136,22 +181,22 @@ func resourceDigitalOceanFirewall() *schema.Resource {
 							},
 						},
 						"destination_addresses": {
-							Type:     schema.TypeList,
+							Type:     schema.TypeSet,
 							Elem:     &schema.Schema{Type: schema.TypeString},
 							Optional: true,
 						},
 						"destination_tags": {
-							Type:     schema.TypeList,
+							Type:     schema.TypeSet,
 							Elem:     &schema.Schema{Type: schema.TypeString},
 							Optional: true,
 						},
 						"destination_droplet_ids": {
-							Type:     schema.TypeList,
+							Type:     schema.TypeSet,
 							Elem:     &schema.Schema{Type: schema.TypeInt},
 							Optional: true,
 						},
 						"destination_load_balancer_uids": {
-							Type:     schema.TypeList,
+							Type:     schema.TypeSet,
 							Elem:     &schema.Schema{Type: schema.TypeString},
 							Optional: true,
 						},
@@ -163,7 +208,7 @@ func resourceDigitalOceanFirewall() *schema.Resource {
 }
 
 func resourceDigitalOceanFirewallCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*godo.Client)
+	client := meta.(*Config).Client
 
 	opts, err := firewallRequest(d, client)
 	if err != nil {
@@ -186,7 +231,7 @@ func resourceDigitalOceanFirewallCreate(d *schema.ResourceData, meta interface{}
 }
 
 func resourceDigitalOceanFirewallRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*godo.Client)
+	client := meta.(*Config).Client
 
 	// Retrieve the firewall properties for updating the state
 	firewall, resp, err := client.Firewalls.Get(context.Background(), d.Id())
@@ -205,40 +250,216 @@ func resourceDigitalOceanFirewallRead(d *schema.ResourceData, meta interface{})
 	d.Set("create_at", firewall.Created)
 	d.Set("pending_changes", firewallPendingChanges(d, firewall))
 	d.Set("name", firewall.Name)
-	d.Set("droplet_ids", firewall.DropletIDs)
-	d.Set("tags", firewall.Tags)
 
-	if err := d.Set("inbound_rule", flattenFirewallInboundRules(d, firewall.InboundRules)); err != nil {
-		return fmt.Errorf("[DEBUG] Error setting Firewall inbound_rule error: %#v", err)
-	}
+	// When manage_membership is false, droplet/tag membership is owned by
+	// digitalocean_firewall_attachment resources instead; leave
+	// droplet_ids/tags out of the refresh so this resource doesn't plan a
+	// diff against membership it doesn't manage.
+	if d.Get("manage_membership").(bool) {
+		d.Set("droplet_ids", firewall.DropletIDs)
+		d.Set("tags", firewall.Tags)
+	}
+
+	// When manage_rules is false, rules are owned by digitalocean_firewall_rule
+	// resources instead; leave inbound_rule/outbound_rule out of the refresh
+	// so this resource doesn't plan a diff against rules it doesn't manage.
+	if d.Get("manage_rules").(bool) {
+		if err := d.Set("inbound_rule", flattenFirewallInboundRules(firewall.InboundRules)); err != nil {
+			return fmt.Errorf("[DEBUG] Error setting Firewall inbound_rule error: %#v", err)
+		}
 
-	if err := d.Set("outbound_rule", flattenFirewallOutboundRules(d, firewall.OutboundRules)); err != nil {
-		return fmt.Errorf("[DEBUG] Error setting Firewall outbound_rule error: %#v", err)
+		if err := d.Set("outbound_rule", flattenFirewallOutboundRules(firewall.OutboundRules)); err != nil {
+			return fmt.Errorf("[DEBUG] Error setting Firewall outbound_rule error: %#v", err)
+		}
 	}
 
 	return nil
 }
 
 func resourceDigitalOceanFirewallUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*godo.Client)
-
-	opts, err := firewallRequest(d, client)
-	if err != nil {
-		return fmt.Errorf("Error in firewall request: %s", err)
+	config := meta.(*Config)
+	client := config.Client
+
+	// Rule changes are applied as a delta via AddRules/RemoveRules rather
+	// than folded into the Firewalls.Update payload below: sending the
+	// full rule list on every update is O(N) on the wire and causes a
+	// brief window where the firewall has neither the old nor the new
+	// policy in place. A delta leaves untouched rules alone.
+	if d.Get("manage_rules").(bool) && (d.HasChange("inbound_rule") || d.HasChange("outbound_rule")) {
+		if err := reconcileFirewallRules(client, d, config.FirewallParallelism); err != nil {
+			return fmt.Errorf("Error reconciling firewall rules: %s", err)
+		}
 	}
 
-	log.Printf("[DEBUG] Firewall update configuration: %#v", opts)
+	if !d.Get("manage_rules").(bool) || !d.Get("manage_membership").(bool) || d.HasChange("name") || d.HasChange("droplet_ids") || d.HasChange("tags") {
+		opts, err := firewallRequest(d, client)
+		if err != nil {
+			return fmt.Errorf("Error in firewall request: %s", err)
+		}
+
+		log.Printf("[DEBUG] Firewall update configuration: %#v", opts)
 
-	_, _, err = client.Firewalls.Update(context.Background(), d.Id(), opts)
-	if err != nil {
-		return fmt.Errorf("Error updating firewall: %s", err)
+		if _, _, err := client.Firewalls.Update(context.Background(), d.Id(), opts); err != nil {
+			return fmt.Errorf("Error updating firewall: %s", err)
+		}
 	}
 
 	return resourceDigitalOceanFirewallRead(d, meta)
 }
 
+// firewallRuleOp is a single AddRules or RemoveRules call to apply as part
+// of reconciling a rule diff.
+type firewallRuleOp struct {
+	remove   bool
+	inbound  *godo.InboundRule
+	outbound *godo.OutboundRule
+}
+
+// reconcileFirewallRules diffs the old and new inbound_rule/outbound_rule
+// sets and applies the resulting adds/removals with a bounded worker pool,
+// each worker pausing briefly between requests to avoid tripping the API's
+// rate limit on firewalls with a large number of rules.
+//
+// Editing an existing rule (e.g. adding a source_tag, changing port_range)
+// is a TypeSet diff of a remove paired with an add for the rule's new
+// shape. Applying every add before any remove guarantees that pair never
+// leaves a window with neither the old nor the new rule in place; the
+// tradeoff is a brief window with both old and new live, which is the
+// safer direction to err on.
+func reconcileFirewallRules(client *godo.Client, d *schema.ResourceData, parallelism int) error {
+	adds, removes := firewallRuleDiffOps(d)
+
+	var errs *multierror.Error
+	if err := applyFirewallRuleOps(client, d.Id(), adds, parallelism); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	if err := applyFirewallRuleOps(client, d.Id(), removes, parallelism); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+
+	return errs.ErrorOrNil()
+}
+
+// applyFirewallRuleOps runs ops to completion on a worker pool bounded by
+// parallelism. Callers are responsible for sequencing separate phases
+// (e.g. adds before removes); ops within a single call to
+// applyFirewallRuleOps carry no ordering guarantee relative to one another.
+func applyFirewallRuleOps(client *godo.Client, firewallID string, ops []firewallRuleOp, parallelism int) error {
+	if len(ops) == 0 {
+		return nil
+	}
+
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	jobs := make(chan firewallRuleOp)
+	results := make(chan error)
+
+	var wg sync.WaitGroup
+	for i := 0; i < parallelism; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for op := range jobs {
+				results <- applyFirewallRuleOp(client, firewallID, op)
+				time.Sleep(firewallRuleThrottle)
+			}
+		}()
+	}
+
+	go func() {
+		for _, op := range ops {
+			jobs <- op
+		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+
+	var errs *multierror.Error
+	for err := range results {
+		if err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
+
+	return errs.ErrorOrNil()
+}
+
+func applyFirewallRuleOp(client *godo.Client, firewallID string, op firewallRuleOp) error {
+	req := &godo.FirewallRulesRequest{}
+	if op.inbound != nil {
+		req.InboundRules = []godo.InboundRule{*op.inbound}
+	} else {
+		req.OutboundRules = []godo.OutboundRule{*op.outbound}
+	}
+
+	if op.remove {
+		if _, err := client.Firewalls.RemoveRules(context.Background(), firewallID, req); err != nil {
+			return fmt.Errorf("Error removing firewall rule: %s", err)
+		}
+		return nil
+	}
+
+	if _, err := client.Firewalls.AddRules(context.Background(), firewallID, req); err != nil {
+		return fmt.Errorf("Error adding firewall rule: %s", err)
+	}
+	return nil
+}
+
+// firewallRuleDiffOps computes the AddRules/RemoveRules operations needed
+// to bring the remote firewall's rules in line with configuration, split
+// into adds and removes so callers can sequence the two phases.
+func firewallRuleDiffOps(d *schema.ResourceData) (adds, removes []firewallRuleOp) {
+	if d.HasChange("inbound_rule") {
+		old, new := d.GetChange("inbound_rule")
+		a, r := diffInboundRuleOps(old.(*schema.Set), new.(*schema.Set))
+		adds = append(adds, a...)
+		removes = append(removes, r...)
+	}
+
+	if d.HasChange("outbound_rule") {
+		old, new := d.GetChange("outbound_rule")
+		a, r := diffOutboundRuleOps(old.(*schema.Set), new.(*schema.Set))
+		adds = append(adds, a...)
+		removes = append(removes, r...)
+	}
+
+	return adds, removes
+}
+
+// diffInboundRuleOps compares an inbound_rule set's old and new values and
+// returns the adds/removes needed to reconcile them. Split out of
+// firewallRuleDiffOps so the diffing logic can be unit tested without
+// going through schema.ResourceData.
+func diffInboundRuleOps(oldSet, newSet *schema.Set) (adds, removes []firewallRuleOp) {
+	for _, raw := range oldSet.Difference(newSet).List() {
+		rule := expandFirewallInboundRule(raw.(map[string]interface{}))
+		removes = append(removes, firewallRuleOp{remove: true, inbound: &rule})
+	}
+	for _, raw := range newSet.Difference(oldSet).List() {
+		rule := expandFirewallInboundRule(raw.(map[string]interface{}))
+		adds = append(adds, firewallRuleOp{remove: false, inbound: &rule})
+	}
+	return adds, removes
+}
+
+// diffOutboundRuleOps is the outbound_rule counterpart to diffInboundRuleOps.
+func diffOutboundRuleOps(oldSet, newSet *schema.Set) (adds, removes []firewallRuleOp) {
+	for _, raw := range oldSet.Difference(newSet).List() {
+		rule := expandFirewallOutboundRule(raw.(map[string]interface{}))
+		removes = append(removes, firewallRuleOp{remove: true, outbound: &rule})
+	}
+	for _, raw := range newSet.Difference(oldSet).List() {
+		rule := expandFirewallOutboundRule(raw.(map[string]interface{}))
+		adds = append(adds, firewallRuleOp{remove: false, outbound: &rule})
+	}
+	return adds, removes
+}
+
 func resourceDigitalOceanFirewallDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*godo.Client)
+	client := meta.(*Config).Client
 
 	log.Printf("[INFO] Deleting firewall: %s", d.Id())
 
@@ -257,134 +478,148 @@ func resourceDigitalOceanFirewallDelete(d *schema.ResourceData, meta interface{}
 	return nil
 }
 
-func resourceDigitalOceanFirewallExists(d *schema.ResourceData, meta interface{}) (bool, error) {
-	client := meta.(*godo.Client)
-
-	log.Printf("[INFO] Exists firewall: %s", d.Id())
-
-	// Retrieve the firewall properties for updating the state
-	_, resp, err := client.Firewalls.Get(context.Background(), d.Id())
-	if err != nil {
-		// check if the firewall no longer exists.
-		if resp != nil && resp.StatusCode == 404 {
-			log.Printf("[WARN] DigitalOcean Firewall (%s) not found", d.Id())
-			d.SetId("")
-			return false, nil
-		}
-
-		return false, fmt.Errorf("Error retrieving firewall: %s", err)
-	}
-
-	return true, nil
-}
-
 func firewallRequest(d *schema.ResourceData, client *godo.Client) (*godo.FirewallRequest, error) {
 	// Build up our firewall request
 	opts := &godo.FirewallRequest{
 		Name: d.Get("name").(string),
 	}
 
-	if v, ok := d.GetOk("droplet_ids"); ok {
-		var droplets []int
-		for _, id := range v.([]interface{}) {
-			i, err := strconv.Atoi(id.(string))
-			if err != nil {
-				return nil, err
+	manageRules := d.Get("manage_rules").(bool)
+	manageMembership := d.Get("manage_membership").(bool)
+
+	// Fetch the remote firewall once up front if either flag is false: both
+	// the rule-preserve and membership-preserve branches below need it, and
+	// a single resource shouldn't issue the same Get twice in one request.
+	var remote *godo.Firewall
+	if (!manageRules || !manageMembership) && d.Id() != "" {
+		firewall, _, err := client.Firewalls.Get(context.Background(), d.Id())
+		if err != nil {
+			return nil, fmt.Errorf("Error retrieving firewall: %s", err)
+		}
+		remote = firewall
+	}
+
+	if manageMembership {
+		if v, ok := d.GetOk("droplet_ids"); ok {
+			var droplets []int
+			for _, id := range v.(*schema.Set).List() {
+				i, err := strconv.Atoi(id.(string))
+				if err != nil {
+					return nil, err
+				}
+				droplets = append(droplets, i)
 			}
-			droplets = append(droplets, i)
+			opts.DropletIDs = droplets
 		}
-		opts.DropletIDs = droplets
-	}
 
-	if v, ok := d.GetOk("tags"); ok {
-		var tags []string
-		for _, tag := range v.([]interface{}) {
-			tags = append(tags, tag.(string))
+		if v, ok := d.GetOk("tags"); ok {
+			var tags []string
+			for _, tag := range v.(*schema.Set).List() {
+				tags = append(tags, tag.(string))
+			}
+			opts.Tags = tags
 		}
-		opts.Tags = tags
+	} else if remote != nil {
+		// manage_membership is false: this resource doesn't own droplet/tag
+		// membership, so preserve whatever is already attached remotely
+		// (e.g. by digitalocean_firewall_attachment) rather than sending an
+		// empty list, which would detach it on the next Update.
+		opts.DropletIDs = remote.DropletIDs
+		opts.Tags = remote.Tags
 	}
 
-	// Get inbound_rules
-	opts.InboundRules = expandFirewallInboundRules(d)
+	if manageRules {
+		opts.InboundRules = expandFirewallInboundRules(d)
+		opts.OutboundRules = expandFirewallOutboundRules(d)
+		return opts, nil
+	}
 
-	// Get outbound_rules
-	opts.OutboundRules = expandFirewallOutboundRules(d)
+	// manage_rules is false: this resource doesn't own the rule set, so
+	// preserve whatever rules already exist remotely (e.g. ones attached by
+	// digitalocean_firewall_rule) rather than sending an empty list, which
+	// would wipe them out on the next Update.
+	if remote != nil {
+		opts.InboundRules = remote.InboundRules
+		opts.OutboundRules = remote.OutboundRules
+	}
 
 	return opts, nil
 }
 
 func expandFirewallInboundRules(d *schema.ResourceData) []godo.InboundRule {
-	rules := make([]godo.InboundRule, 0, len(d.Get("inbound_rule").([]interface{})))
-	for _, rawRule := range d.Get("inbound_rule").([]interface{}) {
-		var src godo.Sources
+	rawRules := d.Get("inbound_rule").(*schema.Set).List()
+	rules := make([]godo.InboundRule, 0, len(rawRules))
+	for _, rawRule := range rawRules {
+		rules = append(rules, expandFirewallInboundRule(rawRule.(map[string]interface{})))
+	}
+	return rules
+}
 
-		rule := rawRule.(map[string]interface{})
+func expandFirewallInboundRule(rule map[string]interface{}) godo.InboundRule {
+	var src godo.Sources
 
-		sourceAddresses := rule["source_addresses"].([]interface{})
-		for _, address := range sourceAddresses {
-			src.Addresses = append(src.Addresses, address.(string))
-		}
+	for _, address := range rule["source_addresses"].(*schema.Set).List() {
+		src.Addresses = append(src.Addresses, address.(string))
+	}
 
-		sourceTags := rule["source_tags"].([]interface{})
-		for _, tag := range sourceTags {
-			src.Tags = append(src.Tags, tag.(string))
-		}
+	for _, tag := range rule["source_tags"].(*schema.Set).List() {
+		src.Tags = append(src.Tags, tag.(string))
+	}
 
-		dropletIds := rule["source_droplet_ids"].([]interface{})
-		for _, dropletId := range dropletIds {
-			src.DropletIDs = append(src.DropletIDs, dropletId.(int))
-		}
+	for _, dropletId := range rule["source_droplet_ids"].(*schema.Set).List() {
+		src.DropletIDs = append(src.DropletIDs, dropletId.(int))
+	}
 
-		lbIds := rule["source_load_balancer_uids"].([]interface{})
-		for _, lbId := range lbIds {
-			src.LoadBalancerUIDs = append(src.LoadBalancerUIDs, lbId.(string))
-		}
+	for _, lbId := range rule["source_load_balancer_uids"].(*schema.Set).List() {
+		src.LoadBalancerUIDs = append(src.LoadBalancerUIDs, lbId.(string))
+	}
 
-		r := godo.InboundRule{
-			Protocol:  rule["protocol"].(string),
-			PortRange: rule["port_range"].(string),
-			Sources:   &src,
-		}
-		rules = append(rules, r)
+	protocol := rule["protocol"].(string)
+	return godo.InboundRule{
+		Protocol: protocol,
+		// ICMP rules have no port range; normalize it away so the
+		// request matches what the API will echo back on Read.
+		PortRange: normalizeFirewallPortRange(protocol, rule["port_range"].(string)),
+		Sources:   &src,
 	}
-	return rules
 }
 
 func expandFirewallOutboundRules(d *schema.ResourceData) []godo.OutboundRule {
-	rules := make([]godo.OutboundRule, 0, len(d.Get("outbound_rule").([]interface{})))
-	for _, rawRule := range d.Get("outbound_rule").([]interface{}) {
-		var dest godo.Destinations
+	rawRules := d.Get("outbound_rule").(*schema.Set).List()
+	rules := make([]godo.OutboundRule, 0, len(rawRules))
+	for _, rawRule := range rawRules {
+		rules = append(rules, expandFirewallOutboundRule(rawRule.(map[string]interface{})))
+	}
+	return rules
+}
 
-		rule := rawRule.(map[string]interface{})
+func expandFirewallOutboundRule(rule map[string]interface{}) godo.OutboundRule {
+	var dest godo.Destinations
 
-		destinationAddresses := rule["destination_addresses"].([]interface{})
-		for _, address := range destinationAddresses {
-			dest.Addresses = append(dest.Addresses, address.(string))
-		}
+	for _, address := range rule["destination_addresses"].(*schema.Set).List() {
+		dest.Addresses = append(dest.Addresses, address.(string))
+	}
 
-		destinationTags := rule["destination_tags"].([]interface{})
-		for _, tag := range destinationTags {
-			dest.Tags = append(dest.Tags, tag.(string))
-		}
+	for _, tag := range rule["destination_tags"].(*schema.Set).List() {
+		dest.Tags = append(dest.Tags, tag.(string))
+	}
 
-		dropletIds := rule["destination_droplet_ids"].([]interface{})
-		for _, dropletId := range dropletIds {
-			dest.DropletIDs = append(dest.DropletIDs, dropletId.(int))
-		}
+	for _, dropletId := range rule["destination_droplet_ids"].(*schema.Set).List() {
+		dest.DropletIDs = append(dest.DropletIDs, dropletId.(int))
+	}
 
-		lbIds := rule["destination_load_balancer_uids"].([]interface{})
-		for _, lbId := range lbIds {
-			dest.LoadBalancerUIDs = append(dest.LoadBalancerUIDs, lbId.(string))
-		}
+	for _, lbId := range rule["destination_load_balancer_uids"].(*schema.Set).List() {
+		dest.LoadBalancerUIDs = append(dest.LoadBalancerUIDs, lbId.(string))
+	}
 
-		r := godo.OutboundRule{
-			Protocol:     rule["protocol"].(string),
-			PortRange:    rule["port_range"].(string),
-			Destinations: &dest,
-		}
-		rules = append(rules, r)
+	protocol := rule["protocol"].(string)
+	return godo.OutboundRule{
+		Protocol: protocol,
+		// ICMP rules have no port range; normalize it away so the
+		// request matches what the API will echo back on Read.
+		PortRange:    normalizeFirewallPortRange(protocol, rule["port_range"].(string)),
+		Destinations: &dest,
 	}
-	return rules
 }
 
 func firewallPendingChanges(d *schema.ResourceData, firewall *godo.Firewall) []interface{} {
@@ -400,15 +635,13 @@ func firewallPendingChanges(d *schema.ResourceData, firewall *godo.Firewall) []i
 	return remote
 }
 
-func flattenFirewallInboundRules(d *schema.ResourceData, rules []godo.InboundRule) []interface{} {
-	if rules == nil {
-		return nil
-	}
-
-	// Prepare the data.
-	local := d.Get("inbound_rule").([]interface{})
+// flattenFirewallInboundRules converts the rules returned by the API into
+// the flat representation used by the schema. Because inbound_rule is a
+// TypeSet, Terraform itself is responsible for matching remote rules back
+// to the local configuration by hash; there is no need to reconcile the
+// two lists by hand here.
+func flattenFirewallInboundRules(rules []godo.InboundRule) []interface{} {
 	remote := make([]interface{}, 0, len(rules))
-	remoteMap := make(map[int]map[string]interface{})
 	for _, rule := range rules {
 		rawRule := map[string]interface{}{
 			"protocol":                  rule.Protocol,
@@ -419,58 +652,14 @@ func flattenFirewallInboundRules(d *schema.ResourceData, rules []godo.InboundRul
 			"source_load_balancer_uids": rule.Sources.LoadBalancerUIDs,
 		}
 		remote = append(remote, rawRule)
-		hash := hashFirewallRule(rule.Protocol, rule.PortRange)
-		remoteMap[hash] = rawRule
-	}
-
-	// Handle special cases, both using the remote rules.
-	if len(remote) == 0 || len(local) == 0 {
-		return remote
-	}
-
-	// Update the local rules to only contains rules match
-	// to the remote rules.
-	match := make([]interface{}, 0, len(rules))
-	for _, rawRule := range local {
-		local := rawRule.(map[string]interface{})
-		protocol := local["protocol"].(string)
-		portRange := local["port_range"].(string)
-		hash := hashFirewallRule(protocol, portRange)
-		remote, ok := remoteMap[hash]
-		if !ok {
-			// No entry in the remote, remove it.
-			continue
-		}
-
-		// matches source lists.
-		key := "source_droplet_ids"
-		local[key] = matchFirewallIntLists(key, local, remote)
-		keys := []string{
-			"source_tags",
-			"source_addresses",
-			"source_load_balancer_uids",
-		}
-		for _, key := range keys {
-			local[key] = matchFirewallStringLists(key, local, remote)
-		}
-
-		match = append(match, local)
-		delete(remoteMap, hash)
 	}
-
-	// Append the remaining remote rules.
-	for _, rawRule := range remoteMap {
-		match = append(match, rawRule)
-	}
-
-	return match
+	return remote
 }
 
-func flattenFirewallOutboundRules(d *schema.ResourceData, rules []godo.OutboundRule) []interface{} {
-	// Prepare the data.
-	local := d.Get("outbound_rule").([]interface{})
+// flattenFirewallOutboundRules is the outbound counterpart of
+// flattenFirewallInboundRules.
+func flattenFirewallOutboundRules(rules []godo.OutboundRule) []interface{} {
 	remote := make([]interface{}, 0, len(rules))
-	remoteMap := make(map[int]map[string]interface{})
 	for _, rule := range rules {
 		rawRule := map[string]interface{}{
 			"protocol":                       rule.Protocol,
@@ -481,110 +670,111 @@ func flattenFirewallOutboundRules(d *schema.ResourceData, rules []godo.OutboundR
 			"destination_load_balancer_uids": rule.Destinations.LoadBalancerUIDs,
 		}
 		remote = append(remote, rawRule)
-		hash := hashFirewallRule(rule.Protocol, rule.PortRange)
-		remoteMap[hash] = rawRule
-	}
-
-	// Handle special cases, both using the remote rules.
-	if len(remote) == 0 || len(local) == 0 {
-		return remote
-	}
-
-	// Update the local rules to only contains rules match
-	// to the remote rules.
-	match := make([]interface{}, 0, len(rules))
-	for _, rawRule := range local {
-		local := rawRule.(map[string]interface{})
-		protocol := local["protocol"].(string)
-		portRange := local["port_range"].(string)
-		hash := hashFirewallRule(protocol, portRange)
-		remote, ok := remoteMap[hash]
-		if !ok {
-			// No entry in the remote, remove it.
-			continue
-		}
-
-		// matches destination lists.
-		key := "destination_droplet_ids"
-		local[key] = matchFirewallIntLists(key, local, remote)
-		keys := []string{
-			"destination_tags",
-			"destination_addresses",
-			"destination_load_balancer_uids",
-		}
-		for _, key := range keys {
-			local[key] = matchFirewallStringLists(key, local, remote)
-		}
-
-		match = append(match, local)
-		delete(remoteMap, hash)
 	}
+	return remote
+}
 
-	// Append the remaining remote rules.
-	for _, rawRule := range remoteMap {
-		match = append(match, rawRule)
+// normalizeFirewallPortRange clears the port range for ICMP rules, which
+// the API neither accepts nor returns one for. Normalizing here (rather
+// than relying on a DiffSuppressFunc) keeps the set hash of a rule stable
+// regardless of what a user happens to put in port_range for an ICMP rule.
+func normalizeFirewallPortRange(protocol, portRange string) string {
+	if protocol == "icmp" {
+		return ""
 	}
-
-	return match
+	return portRange
 }
 
-func matchFirewallIntLists(key string, local, remote map[string]interface{}) []interface{} {
-	remoteSize := len(remote[key].([]int))
-	remoteSet := make(map[int]bool)
-	matchedList := make([]interface{}, 0, remoteSize)
+func validateFirewallPortRange(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+	if value == "" || value == "0" || value == "all" {
+		return
+	}
 
-	// Create a remote set out of the list for the quick comparison.
-	for _, i := range remote[key].([]int) {
-		remoteSet[i] = true
+	ports := strings.Split(value, "-")
+	if len(ports) > 2 {
+		errors = append(errors, fmt.Errorf(
+			"%q must be \"all\", a single port (e.g. \"80\"), or a port range (e.g. \"8000-9000\"), got: %q", k, value))
+		return
 	}
 
-	// Add only the item which exists in the remote list.
-	for _, i := range local[key].([]interface{}) {
-		if _, ok := remoteSet[i.(int)]; !ok {
-			continue
+	parsed := make([]int, 0, len(ports))
+	for _, p := range ports {
+		port, err := strconv.Atoi(p)
+		if err != nil {
+			errors = append(errors, fmt.Errorf("%q must contain only numeric ports, got: %q", k, value))
+			return
 		}
-		matchedList = append(matchedList, i)
-		delete(remoteSet, i.(int))
+		if port < 1 || port > 65535 {
+			errors = append(errors, fmt.Errorf("%q ports must be between 1 and 65535, got: %q", k, value))
+			return
+		}
+		parsed = append(parsed, port)
 	}
 
-	// Append items only exists in the remote list.
-	for i := range remoteSet {
-		matchedList = append(matchedList, i)
+	if len(parsed) == 2 && parsed[0] >= parsed[1] {
+		errors = append(errors, fmt.Errorf("%q range must have a \"from\" port less than the \"to\" port, got: %q", k, value))
 	}
 
-	return matchedList
+	return
 }
 
-func matchFirewallStringLists(key string, local, remote map[string]interface{}) []interface{} {
-	remoteSize := len(remote[key].([]string))
-	remoteList := make([]interface{}, 0, remoteSize)
-	matchedList := make([]interface{}, 0, remoteSize)
-
-	// Create a remote set out of the list for the quick comparison.
-	for _, s := range remote[key].([]string) {
-		remoteList = append(remoteList, s)
-	}
-	remoteSet := schema.NewSet(schema.HashString, remoteList)
+func resourceDigitalOceanFirewallInboundRuleHash(v interface{}) int {
+	var buf bytes.Buffer
+	m := v.(map[string]interface{})
 
-	// Add only the item which exists in the remote list.
-	for _, s := range local[key].([]interface{}) {
-		if !remoteSet.Contains(s.(string)) {
-			continue
-		}
-		matchedList = append(matchedList, s)
-		remoteSet.Remove(s)
-	}
+	protocol := m["protocol"].(string)
+	buf.WriteString(fmt.Sprintf("%s-", protocol))
+	buf.WriteString(fmt.Sprintf("%s-", normalizeFirewallPortRange(protocol, m["port_range"].(string))))
 
-	// Append items only exists in the remote list.
-	for _, s := range remoteSet.List() {
-		matchedList = append(matchedList, s)
-	}
+	writeFirewallStringSet(&buf, m["source_addresses"].(*schema.Set))
+	writeFirewallStringSet(&buf, m["source_tags"].(*schema.Set))
+	writeFirewallIntSet(&buf, m["source_droplet_ids"].(*schema.Set))
+	writeFirewallStringSet(&buf, m["source_load_balancer_uids"].(*schema.Set))
 
-	return matchedList
+	return hashcode.String(buf.String())
 }
 
-func hashFirewallRule(protocol, portRange string) int {
+func resourceDigitalOceanFirewallOutboundRuleHash(v interface{}) int {
 	var buf bytes.Buffer
-	buf.WriteString(fmt.Sprintf("%s-%s", protocol, portRange))
+	m := v.(map[string]interface{})
+
+	protocol := m["protocol"].(string)
+	buf.WriteString(fmt.Sprintf("%s-", protocol))
+	buf.WriteString(fmt.Sprintf("%s-", normalizeFirewallPortRange(protocol, m["port_range"].(string))))
+
+	writeFirewallStringSet(&buf, m["destination_addresses"].(*schema.Set))
+	writeFirewallStringSet(&buf, m["destination_tags"].(*schema.Set))
+	writeFirewallIntSet(&buf, m["destination_droplet_ids"].(*schema.Set))
+	writeFirewallStringSet(&buf, m["destination_load_balancer_uids"].(*schema.Set))
+
 	return hashcode.String(buf.String())
 }
+
+// writeFirewallStringSet writes a sorted, order-independent representation
+// of a string set into buf so that rule hashes are stable regardless of
+// the order elements were declared in configuration.
+func writeFirewallStringSet(buf *bytes.Buffer, s *schema.Set) {
+	raw := s.List()
+	values := make([]string, 0, len(raw))
+	for _, v := range raw {
+		values = append(values, v.(string))
+	}
+	sort.Strings(values)
+	for _, v := range values {
+		buf.WriteString(fmt.Sprintf("%s-", v))
+	}
+}
+
+// writeFirewallIntSet is the int counterpart of writeFirewallStringSet.
+func writeFirewallIntSet(buf *bytes.Buffer, s *schema.Set) {
+	raw := s.List()
+	values := make([]int, 0, len(raw))
+	for _, v := range raw {
+		values = append(values, v.(int))
+	}
+	sort.Ints(values)
+	for _, v := range values {
+		buf.WriteString(fmt.Sprintf("%d-", v))
+	}
+}
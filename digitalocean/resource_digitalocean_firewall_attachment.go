@@ -0,0 +1,219 @@
+package digitalocean
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/digitalocean/godo"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// resourceDigitalOceanFirewallAttachment manages droplet and tag membership
+// on an existing digitalocean_firewall without owning the firewall itself,
+// so a platform team can own the firewall's rule policy while application
+// teams attach their own droplets independently. Only the droplets/tags
+// this resource declares are reconciled; Read, Update, and Delete all
+// operate on that managed subset so other attachments (or the firewall
+// resource itself) aren't disturbed.
+func resourceDigitalOceanFirewallAttachment() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceDigitalOceanFirewallAttachmentCreate,
+		Read:   resourceDigitalOceanFirewallAttachmentRead,
+		Update: resourceDigitalOceanFirewallAttachmentUpdate,
+		Delete: resourceDigitalOceanFirewallAttachmentDelete,
+
+		Schema: map[string]*schema.Schema{
+			"firewall_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"droplet_ids": {
+				Type:     schema.TypeSet,
+				Elem:     &schema.Schema{Type: schema.TypeInt},
+				Optional: true,
+			},
+
+			"tags": {
+				Type:     schema.TypeSet,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Optional: true,
+			},
+		},
+	}
+}
+
+func resourceDigitalOceanFirewallAttachmentCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Config).Client
+	firewallID := d.Get("firewall_id").(string)
+
+	if err := addFirewallAttachmentMembers(client, firewallID, expandFirewallAttachmentDropletIDs(d), expandFirewallAttachmentTags(d)); err != nil {
+		return err
+	}
+
+	d.SetId(resource.PrefixedUniqueId(fmt.Sprintf("%s-", firewallID)))
+
+	return resourceDigitalOceanFirewallAttachmentRead(d, meta)
+}
+
+func resourceDigitalOceanFirewallAttachmentRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Config).Client
+	firewallID := d.Get("firewall_id").(string)
+
+	firewall, resp, err := client.Firewalls.Get(context.Background(), firewallID)
+	if err != nil {
+		if resp != nil && resp.StatusCode == 404 {
+			log.Printf("[WARN] DigitalOcean Firewall (%s) not found, removing attachment from state", firewallID)
+			d.SetId("")
+			return nil
+		}
+
+		return fmt.Errorf("Error retrieving firewall: %s", err)
+	}
+
+	remoteDroplets := make(map[int]bool)
+	for _, id := range firewall.DropletIDs {
+		remoteDroplets[id] = true
+	}
+
+	remoteTags := make(map[string]bool)
+	for _, tag := range firewall.Tags {
+		remoteTags[tag] = true
+	}
+
+	// Only keep the subset this resource manages that is still actually
+	// attached remotely; anything removed out-of-band drops out of state
+	// here and will be re-added on the next apply.
+	managedDroplets := make([]interface{}, 0)
+	for _, id := range d.Get("droplet_ids").(*schema.Set).List() {
+		if remoteDroplets[id.(int)] {
+			managedDroplets = append(managedDroplets, id)
+		}
+	}
+
+	managedTags := make([]interface{}, 0)
+	for _, tag := range d.Get("tags").(*schema.Set).List() {
+		if remoteTags[tag.(string)] {
+			managedTags = append(managedTags, tag)
+		}
+	}
+
+	d.Set("droplet_ids", managedDroplets)
+	d.Set("tags", managedTags)
+
+	return nil
+}
+
+func resourceDigitalOceanFirewallAttachmentUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Config).Client
+	firewallID := d.Get("firewall_id").(string)
+
+	if d.HasChange("droplet_ids") {
+		old, new := d.GetChange("droplet_ids")
+		removed := intSetToSlice(old.(*schema.Set).Difference(new.(*schema.Set)))
+		added := intSetToSlice(new.(*schema.Set).Difference(old.(*schema.Set)))
+
+		if len(removed) > 0 {
+			if _, err := client.Firewalls.RemoveDroplets(context.Background(), firewallID, removed...); err != nil {
+				return fmt.Errorf("Error removing droplets from firewall: %s", err)
+			}
+		}
+
+		if len(added) > 0 {
+			if _, err := client.Firewalls.AddDroplets(context.Background(), firewallID, added...); err != nil {
+				return fmt.Errorf("Error adding droplets to firewall: %s", err)
+			}
+		}
+	}
+
+	if d.HasChange("tags") {
+		old, new := d.GetChange("tags")
+		removed := stringSetToSlice(old.(*schema.Set).Difference(new.(*schema.Set)))
+		added := stringSetToSlice(new.(*schema.Set).Difference(old.(*schema.Set)))
+
+		if len(removed) > 0 {
+			if _, err := client.Firewalls.RemoveTags(context.Background(), firewallID, removed...); err != nil {
+				return fmt.Errorf("Error removing tags from firewall: %s", err)
+			}
+		}
+
+		if len(added) > 0 {
+			if _, err := client.Firewalls.AddTags(context.Background(), firewallID, added...); err != nil {
+				return fmt.Errorf("Error adding tags to firewall: %s", err)
+			}
+		}
+	}
+
+	return resourceDigitalOceanFirewallAttachmentRead(d, meta)
+}
+
+func resourceDigitalOceanFirewallAttachmentDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Config).Client
+	firewallID := d.Get("firewall_id").(string)
+
+	// Only detach the droplets/tags this resource added; the firewall
+	// itself and any other attachments are left untouched.
+	dropletIDs := expandFirewallAttachmentDropletIDs(d)
+	if len(dropletIDs) > 0 {
+		_, err := client.Firewalls.RemoveDroplets(context.Background(), firewallID, dropletIDs...)
+		if err != nil && !strings.Contains(err.Error(), "404 Not Found") {
+			return fmt.Errorf("Error removing droplets from firewall: %s", err)
+		}
+	}
+
+	tags := expandFirewallAttachmentTags(d)
+	if len(tags) > 0 {
+		_, err := client.Firewalls.RemoveTags(context.Background(), firewallID, tags...)
+		if err != nil && !strings.Contains(err.Error(), "404 Not Found") {
+			return fmt.Errorf("Error removing tags from firewall: %s", err)
+		}
+	}
+
+	return nil
+}
+
+func addFirewallAttachmentMembers(client *godo.Client, firewallID string, dropletIDs []int, tags []string) error {
+	if len(dropletIDs) > 0 {
+		if _, err := client.Firewalls.AddDroplets(context.Background(), firewallID, dropletIDs...); err != nil {
+			return fmt.Errorf("Error adding droplets to firewall: %s", err)
+		}
+	}
+
+	if len(tags) > 0 {
+		if _, err := client.Firewalls.AddTags(context.Background(), firewallID, tags...); err != nil {
+			return fmt.Errorf("Error adding tags to firewall: %s", err)
+		}
+	}
+
+	return nil
+}
+
+func expandFirewallAttachmentDropletIDs(d *schema.ResourceData) []int {
+	return intSetToSlice(d.Get("droplet_ids").(*schema.Set))
+}
+
+func expandFirewallAttachmentTags(d *schema.ResourceData) []string {
+	return stringSetToSlice(d.Get("tags").(*schema.Set))
+}
+
+func intSetToSlice(s *schema.Set) []int {
+	list := s.List()
+	ids := make([]int, 0, len(list))
+	for _, v := range list {
+		ids = append(ids, v.(int))
+	}
+	return ids
+}
+
+func stringSetToSlice(s *schema.Set) []string {
+	list := s.List()
+	values := make([]string, 0, len(list))
+	for _, v := range list {
+		values = append(values, v.(string))
+	}
+	return values
+}
@@ -0,0 +1,93 @@
+package digitalocean
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccDigitalOceanFirewallAttachment_Basic(t *testing.T) {
+	firewallName := fmt.Sprintf("firewall-%s", acctest.RandString(10))
+	dropletName := fmt.Sprintf("droplet-%s", acctest.RandString(10))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDigitalOceanFirewallDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckDigitalOceanFirewallAttachmentConfigBasic(dropletName, firewallName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDigitalOceanFirewallAttachmentExists("digitalocean_firewall_attachment.foobar"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckDigitalOceanFirewallAttachmentExists(resourceName string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("not found: %s", resourceName)
+		}
+
+		firewallID := rs.Primary.Attributes["firewall_id"]
+
+		// droplet_ids is a set, so its elements are keyed by hash rather
+		// than by position.
+		var dropletID string
+		for k, v := range rs.Primary.Attributes {
+			if strings.HasPrefix(k, "droplet_ids.") && k != "droplet_ids.#" {
+				dropletID = v
+				break
+			}
+		}
+
+		client := testAccProvider.Meta().(*Config).Client
+		firewall, _, err := client.Firewalls.Get(context.Background(), firewallID)
+		if err != nil {
+			return err
+		}
+
+		for _, id := range firewall.DropletIDs {
+			if fmt.Sprintf("%d", id) == dropletID {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("droplet %s not attached to firewall %s", dropletID, firewallID)
+	}
+}
+
+func testAccCheckDigitalOceanFirewallAttachmentConfigBasic(dropletName, firewallName string) string {
+	return fmt.Sprintf(`
+resource "digitalocean_droplet" "foobar" {
+  name   = "%s"
+  size   = "s-1vcpu-1gb"
+  image  = "ubuntu-20-04-x64"
+  region = "nyc3"
+}
+
+resource "digitalocean_firewall" "foobar" {
+  name               = "%s"
+  manage_membership = false
+
+  inbound_rule {
+    protocol         = "tcp"
+    port_range       = "22"
+    source_addresses = ["0.0.0.0/0", "::/0"]
+  }
+}
+
+resource "digitalocean_firewall_attachment" "foobar" {
+  firewall_id = digitalocean_firewall.foobar.id
+  droplet_ids = [digitalocean_droplet.foobar.id]
+}
+`, dropletName, firewallName)
+}
@@ -0,0 +1,82 @@
+package digitalocean
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func firewallRuleMap(protocol, portRange string, sourceAddresses []interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"protocol":                  protocol,
+		"port_range":                portRange,
+		"source_addresses":          schema.NewSet(schema.HashString, sourceAddresses),
+		"source_tags":               schema.NewSet(schema.HashString, []interface{}{}),
+		"source_droplet_ids":        schema.NewSet(schema.HashInt, []interface{}{}),
+		"source_load_balancer_uids": schema.NewSet(schema.HashString, []interface{}{}),
+	}
+}
+
+func TestDiffInboundRuleOps_AddOnly(t *testing.T) {
+	oldSet := schema.NewSet(resourceDigitalOceanFirewallInboundRuleHash, []interface{}{})
+	newSet := schema.NewSet(resourceDigitalOceanFirewallInboundRuleHash, []interface{}{
+		firewallRuleMap("tcp", "22", []interface{}{"0.0.0.0/0"}),
+	})
+
+	adds, removes := diffInboundRuleOps(oldSet, newSet)
+	if len(adds) != 1 {
+		t.Fatalf("expected 1 add, got %d", len(adds))
+	}
+	if len(removes) != 0 {
+		t.Fatalf("expected 0 removes, got %d", len(removes))
+	}
+	if adds[0].remove {
+		t.Error("expected add op to have remove=false")
+	}
+	if adds[0].inbound == nil || adds[0].inbound.PortRange != "22" {
+		t.Errorf("expected add op's inbound rule to carry port_range 22, got %#v", adds[0].inbound)
+	}
+}
+
+func TestDiffInboundRuleOps_RemoveOnly(t *testing.T) {
+	oldSet := schema.NewSet(resourceDigitalOceanFirewallInboundRuleHash, []interface{}{
+		firewallRuleMap("tcp", "22", []interface{}{"0.0.0.0/0"}),
+	})
+	newSet := schema.NewSet(resourceDigitalOceanFirewallInboundRuleHash, []interface{}{})
+
+	adds, removes := diffInboundRuleOps(oldSet, newSet)
+	if len(adds) != 0 {
+		t.Fatalf("expected 0 adds, got %d", len(adds))
+	}
+	if len(removes) != 1 {
+		t.Fatalf("expected 1 remove, got %d", len(removes))
+	}
+	if !removes[0].remove {
+		t.Error("expected remove op to have remove=true")
+	}
+}
+
+func TestDiffInboundRuleOps_EditedRuleProducesPairedAddAndRemove(t *testing.T) {
+	oldSet := schema.NewSet(resourceDigitalOceanFirewallInboundRuleHash, []interface{}{
+		firewallRuleMap("tcp", "22", []interface{}{"0.0.0.0/0"}),
+	})
+	newSet := schema.NewSet(resourceDigitalOceanFirewallInboundRuleHash, []interface{}{
+		firewallRuleMap("tcp", "22", []interface{}{"10.0.0.0/8"}),
+	})
+
+	adds, removes := diffInboundRuleOps(oldSet, newSet)
+	if len(adds) != 1 || len(removes) != 1 {
+		t.Fatalf("expected a paired add and remove for an edited rule, got %d adds, %d removes", len(adds), len(removes))
+	}
+}
+
+func TestDiffInboundRuleOps_UnchangedRuleProducesNoOps(t *testing.T) {
+	rule := firewallRuleMap("tcp", "22", []interface{}{"0.0.0.0/0"})
+	oldSet := schema.NewSet(resourceDigitalOceanFirewallInboundRuleHash, []interface{}{rule})
+	newSet := schema.NewSet(resourceDigitalOceanFirewallInboundRuleHash, []interface{}{rule})
+
+	adds, removes := diffInboundRuleOps(oldSet, newSet)
+	if len(adds) != 0 || len(removes) != 0 {
+		t.Fatalf("expected no ops for an unchanged rule, got %d adds, %d removes", len(adds), len(removes))
+	}
+}
@@ -0,0 +1,324 @@
+package digitalocean
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/digitalocean/godo"
+	"github.com/hashicorp/terraform/helper/hashcode"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+// resourceDigitalOceanFirewallRule manages a single inbound or outbound rule
+// against an existing digitalocean_firewall, using godo's AddRules/RemoveRules
+// endpoints instead of the firewall's own Update call. This lets modules that
+// don't own the parent firewall compose its rule set incrementally; see the
+// manage_rules attribute on digitalocean_firewall for how the two resources
+// avoid fighting each other on refresh.
+func resourceDigitalOceanFirewallRule() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceDigitalOceanFirewallRuleCreate,
+		Read:   resourceDigitalOceanFirewallRuleRead,
+		Delete: resourceDigitalOceanFirewallRuleDelete,
+
+		Schema: map[string]*schema.Schema{
+			"firewall_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"inbound", "outbound"}, false),
+			},
+
+			"protocol": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					"tcp", "udp", "icmp",
+				}, false),
+			},
+
+			"port_range": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validateFirewallPortRange,
+			},
+
+			"source_addresses": {
+				Type:     schema.TypeSet,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"source_tags": {
+				Type:     schema.TypeSet,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"source_droplet_ids": {
+				Type:     schema.TypeSet,
+				Elem:     &schema.Schema{Type: schema.TypeInt},
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"source_load_balancer_uids": {
+				Type:     schema.TypeSet,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"destination_addresses": {
+				Type:     schema.TypeSet,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"destination_tags": {
+				Type:     schema.TypeSet,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"destination_droplet_ids": {
+				Type:     schema.TypeSet,
+				Elem:     &schema.Schema{Type: schema.TypeInt},
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"destination_load_balancer_uids": {
+				Type:     schema.TypeSet,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Optional: true,
+				ForceNew: true,
+			},
+		},
+	}
+}
+
+func resourceDigitalOceanFirewallRuleCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Config).Client
+	firewallID := d.Get("firewall_id").(string)
+
+	rulesReq, err := firewallRuleRequest(d)
+	if err != nil {
+		return fmt.Errorf("Error in firewall rule request: %s", err)
+	}
+
+	log.Printf("[DEBUG] Firewall rule create configuration: %#v", rulesReq)
+
+	if _, err := client.Firewalls.AddRules(context.Background(), firewallID, rulesReq); err != nil {
+		return fmt.Errorf("Error adding firewall rule: %s", err)
+	}
+
+	d.SetId(firewallRuleID(firewallID, d))
+
+	return resourceDigitalOceanFirewallRuleRead(d, meta)
+}
+
+func resourceDigitalOceanFirewallRuleRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Config).Client
+	firewallID := d.Get("firewall_id").(string)
+
+	firewall, resp, err := client.Firewalls.Get(context.Background(), firewallID)
+	if err != nil {
+		if resp != nil && resp.StatusCode == 404 {
+			log.Printf("[WARN] DigitalOcean Firewall (%s) not found, removing rule from state", firewallID)
+			d.SetId("")
+			return nil
+		}
+
+		return fmt.Errorf("Error retrieving firewall: %s", err)
+	}
+
+	if !firewallContainsRule(d, firewall) {
+		log.Printf("[WARN] DigitalOcean Firewall Rule (%s) not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	return nil
+}
+
+func resourceDigitalOceanFirewallRuleDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Config).Client
+	firewallID := d.Get("firewall_id").(string)
+
+	rulesReq, err := firewallRuleRequest(d)
+	if err != nil {
+		return fmt.Errorf("Error in firewall rule request: %s", err)
+	}
+
+	log.Printf("[INFO] Removing firewall rule: %s", d.Id())
+
+	_, err = client.Firewalls.RemoveRules(context.Background(), firewallID, rulesReq)
+	if err != nil && strings.Contains(err.Error(), "404 Not Found") {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("Error removing firewall rule: %s", err)
+	}
+
+	return nil
+}
+
+func firewallRuleRequest(d *schema.ResourceData) (*godo.FirewallRulesRequest, error) {
+	protocol := d.Get("protocol").(string)
+	portRange := normalizeFirewallPortRange(protocol, d.Get("port_range").(string))
+
+	switch d.Get("type").(string) {
+	case "inbound":
+		addresses, tags, dropletIDs, lbUIDs := expandFirewallRuleSet(d, "source")
+		return &godo.FirewallRulesRequest{
+			InboundRules: []godo.InboundRule{
+				{
+					Protocol:  protocol,
+					PortRange: portRange,
+					Sources: &godo.Sources{
+						Addresses:        addresses,
+						Tags:             tags,
+						DropletIDs:       dropletIDs,
+						LoadBalancerUIDs: lbUIDs,
+					},
+				},
+			},
+		}, nil
+	case "outbound":
+		addresses, tags, dropletIDs, lbUIDs := expandFirewallRuleSet(d, "destination")
+		return &godo.FirewallRulesRequest{
+			OutboundRules: []godo.OutboundRule{
+				{
+					Protocol:  protocol,
+					PortRange: portRange,
+					Destinations: &godo.Destinations{
+						Addresses:        addresses,
+						Tags:             tags,
+						DropletIDs:       dropletIDs,
+						LoadBalancerUIDs: lbUIDs,
+					},
+				},
+			},
+		}, nil
+	default:
+		return nil, fmt.Errorf("Unknown firewall rule type: %s", d.Get("type").(string))
+	}
+}
+
+// expandFirewallRuleSet reads the source_* or destination_* attributes
+// (selected by prefix) off of d.
+func expandFirewallRuleSet(d *schema.ResourceData, prefix string) (addresses, tags []string, dropletIDs []int, lbUIDs []string) {
+	for _, v := range d.Get(prefix + "_addresses").(*schema.Set).List() {
+		addresses = append(addresses, v.(string))
+	}
+	for _, v := range d.Get(prefix + "_tags").(*schema.Set).List() {
+		tags = append(tags, v.(string))
+	}
+	for _, v := range d.Get(prefix + "_droplet_ids").(*schema.Set).List() {
+		dropletIDs = append(dropletIDs, v.(int))
+	}
+	for _, v := range d.Get(prefix + "_load_balancer_uids").(*schema.Set).List() {
+		lbUIDs = append(lbUIDs, v.(string))
+	}
+	return
+}
+
+// firewallRuleID builds a stable id for a rule out of the fields that
+// identify it remotely (DigitalOcean firewall rules have no id of their
+// own).
+func firewallRuleID(firewallID string, d *schema.ResourceData) string {
+	ruleType := d.Get("type").(string)
+	prefix := "source"
+	if ruleType == "outbound" {
+		prefix = "destination"
+	}
+
+	addresses, tags, dropletIDs, lbUIDs := expandFirewallRuleSet(d, prefix)
+	sig := firewallRuleSignature(d.Get("protocol").(string), d.Get("port_range").(string), addresses, tags, dropletIDs, lbUIDs)
+
+	return fmt.Sprintf("%s-%s-%d", firewallID, ruleType, hashcode.String(sig))
+}
+
+// firewallContainsRule reports whether the firewall's remote rule set
+// contains a rule matching the one configured on d.
+func firewallContainsRule(d *schema.ResourceData, firewall *godo.Firewall) bool {
+	protocol := d.Get("protocol").(string)
+	portRange := d.Get("port_range").(string)
+
+	if d.Get("type").(string) == "inbound" {
+		addresses, tags, dropletIDs, lbUIDs := expandFirewallRuleSet(d, "source")
+		want := firewallRuleSignature(protocol, portRange, addresses, tags, dropletIDs, lbUIDs)
+		for _, rule := range firewall.InboundRules {
+			got := firewallRuleSignature(rule.Protocol, rule.PortRange, rule.Sources.Addresses, rule.Sources.Tags, rule.Sources.DropletIDs, rule.Sources.LoadBalancerUIDs)
+			if got == want {
+				return true
+			}
+		}
+		return false
+	}
+
+	addresses, tags, dropletIDs, lbUIDs := expandFirewallRuleSet(d, "destination")
+	want := firewallRuleSignature(protocol, portRange, addresses, tags, dropletIDs, lbUIDs)
+	for _, rule := range firewall.OutboundRules {
+		got := firewallRuleSignature(rule.Protocol, rule.PortRange, rule.Destinations.Addresses, rule.Destinations.Tags, rule.Destinations.DropletIDs, rule.Destinations.LoadBalancerUIDs)
+		if got == want {
+			return true
+		}
+	}
+	return false
+}
+
+// firewallRuleSignature builds an order-independent canonical string for a
+// rule so two rules built from different (e.g. local vs. remote) slice
+// orderings can be compared for equality.
+func firewallRuleSignature(protocol, portRange string, addresses, tags []string, dropletIDs []int, lbUIDs []string) string {
+	var buf bytes.Buffer
+
+	buf.WriteString(fmt.Sprintf("%s-", protocol))
+	buf.WriteString(fmt.Sprintf("%s-", normalizeFirewallPortRange(protocol, portRange)))
+
+	sortedAddresses := append([]string(nil), addresses...)
+	sort.Strings(sortedAddresses)
+	for _, a := range sortedAddresses {
+		buf.WriteString(fmt.Sprintf("%s-", a))
+	}
+
+	sortedTags := append([]string(nil), tags...)
+	sort.Strings(sortedTags)
+	for _, t := range sortedTags {
+		buf.WriteString(fmt.Sprintf("%s-", t))
+	}
+
+	sortedDropletIDs := append([]int(nil), dropletIDs...)
+	sort.Ints(sortedDropletIDs)
+	for _, id := range sortedDropletIDs {
+		buf.WriteString(fmt.Sprintf("%d-", id))
+	}
+
+	sortedLBUIDs := append([]string(nil), lbUIDs...)
+	sort.Strings(sortedLBUIDs)
+	for _, uid := range sortedLBUIDs {
+		buf.WriteString(fmt.Sprintf("%s-", uid))
+	}
+
+	return buf.String()
+}
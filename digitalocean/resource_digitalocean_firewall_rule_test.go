@@ -0,0 +1,87 @@
+package digitalocean
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccDigitalOceanFirewallRule_Basic(t *testing.T) {
+	name := fmt.Sprintf("firewall-%s", acctest.RandString(10))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDigitalOceanFirewallRuleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckDigitalOceanFirewallRuleConfigBasic(name),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDigitalOceanFirewallRuleExists("digitalocean_firewall_rule.foobar"),
+					resource.TestCheckResourceAttr("digitalocean_firewall_rule.foobar", "type", "inbound"),
+					resource.TestCheckResourceAttr("digitalocean_firewall_rule.foobar", "protocol", "tcp"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckDigitalOceanFirewallRuleExists(resourceName string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("not found: %s", resourceName)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("no firewall rule id is set")
+		}
+
+		client := testAccProvider.Meta().(*Config).Client
+		firewallID := rs.Primary.Attributes["firewall_id"]
+
+		firewall, _, err := client.Firewalls.Get(context.Background(), firewallID)
+		if err != nil {
+			return err
+		}
+
+		for _, rule := range firewall.InboundRules {
+			if rule.Protocol == "tcp" && rule.PortRange == "8080" {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("firewall rule not found on firewall %s", firewallID)
+	}
+}
+
+// testAccCheckDigitalOceanFirewallRuleDestroy doesn't query godo directly:
+// digitalocean_firewall_rule.Delete is best-effort against a firewall that
+// may itself already be gone by the time this runs, so the real assertion
+// is left to testAccCheckDigitalOceanFirewallDestroy, which checks that the
+// parent firewall the rule was attached to has been torn down.
+func testAccCheckDigitalOceanFirewallRuleDestroy(s *terraform.State) error {
+	return testAccCheckDigitalOceanFirewallDestroy(s)
+}
+
+func testAccCheckDigitalOceanFirewallRuleConfigBasic(name string) string {
+	return fmt.Sprintf(`
+resource "digitalocean_firewall" "foobar" {
+  name         = "%s"
+  manage_rules = false
+}
+
+resource "digitalocean_firewall_rule" "foobar" {
+  firewall_id = digitalocean_firewall.foobar.id
+  type        = "inbound"
+  protocol    = "tcp"
+  port_range  = "8080"
+
+  source_addresses = ["0.0.0.0/0", "::/0"]
+}
+`, name)
+}
@@ -0,0 +1,183 @@
+package digitalocean
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/digitalocean/godo"
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestValidateFirewallPortRange(t *testing.T) {
+	validCases := []string{"", "0", "all", "80", "8000-9000", "1-65535"}
+	for _, v := range validCases {
+		if _, errors := validateFirewallPortRange(v, "port_range"); len(errors) != 0 {
+			t.Errorf("expected %q to be valid, got errors: %v", v, errors)
+		}
+	}
+
+	invalidCases := []string{"0-65536", "9000-8000", "8000-8000", "tcp", "1-2-3"}
+	for _, v := range invalidCases {
+		if _, errors := validateFirewallPortRange(v, "port_range"); len(errors) == 0 {
+			t.Errorf("expected %q to be invalid", v)
+		}
+	}
+}
+
+func TestNormalizeFirewallPortRange(t *testing.T) {
+	if got := normalizeFirewallPortRange("icmp", "8000-9000"); got != "" {
+		t.Errorf("expected icmp rules to normalize to an empty port_range, got %q", got)
+	}
+
+	if got := normalizeFirewallPortRange("tcp", "8000-9000"); got != "8000-9000" {
+		t.Errorf("expected tcp port_range to pass through unchanged, got %q", got)
+	}
+}
+
+func TestResourceDigitalOceanFirewallInboundRuleHash_StableUnderReordering(t *testing.T) {
+	base := map[string]interface{}{
+		"protocol":                  "tcp",
+		"port_range":                "80",
+		"source_addresses":          schema.NewSet(schema.HashString, []interface{}{"1.2.3.4/32", "5.6.7.8/32"}),
+		"source_tags":               schema.NewSet(schema.HashString, []interface{}{"web"}),
+		"source_droplet_ids":        schema.NewSet(schema.HashInt, []interface{}{1, 2}),
+		"source_load_balancer_uids": schema.NewSet(schema.HashString, []interface{}{}),
+	}
+	reordered := map[string]interface{}{
+		"protocol":                  "tcp",
+		"port_range":                "80",
+		"source_addresses":          schema.NewSet(schema.HashString, []interface{}{"5.6.7.8/32", "1.2.3.4/32"}),
+		"source_tags":               schema.NewSet(schema.HashString, []interface{}{"web"}),
+		"source_droplet_ids":        schema.NewSet(schema.HashInt, []interface{}{2, 1}),
+		"source_load_balancer_uids": schema.NewSet(schema.HashString, []interface{}{}),
+	}
+
+	if resourceDigitalOceanFirewallInboundRuleHash(base) != resourceDigitalOceanFirewallInboundRuleHash(reordered) {
+		t.Error("expected inbound rule hash to be independent of set element ordering")
+	}
+}
+
+func TestResourceDigitalOceanFirewallInboundRuleHash_ICMPIgnoresPortRange(t *testing.T) {
+	withPortRange := map[string]interface{}{
+		"protocol":                  "icmp",
+		"port_range":                "8000-9000",
+		"source_addresses":          schema.NewSet(schema.HashString, []interface{}{}),
+		"source_tags":               schema.NewSet(schema.HashString, []interface{}{}),
+		"source_droplet_ids":        schema.NewSet(schema.HashInt, []interface{}{}),
+		"source_load_balancer_uids": schema.NewSet(schema.HashString, []interface{}{}),
+	}
+	withoutPortRange := map[string]interface{}{
+		"protocol":                  "icmp",
+		"port_range":                "",
+		"source_addresses":          schema.NewSet(schema.HashString, []interface{}{}),
+		"source_tags":               schema.NewSet(schema.HashString, []interface{}{}),
+		"source_droplet_ids":        schema.NewSet(schema.HashInt, []interface{}{}),
+		"source_load_balancer_uids": schema.NewSet(schema.HashString, []interface{}{}),
+	}
+
+	if resourceDigitalOceanFirewallInboundRuleHash(withPortRange) != resourceDigitalOceanFirewallInboundRuleHash(withoutPortRange) {
+		t.Error("expected icmp rules to hash the same regardless of a stray port_range value")
+	}
+}
+
+func TestResourceDigitalOceanFirewallOutboundRuleHash_StableUnderReordering(t *testing.T) {
+	base := map[string]interface{}{
+		"protocol":                       "udp",
+		"port_range":                     "53",
+		"destination_addresses":          schema.NewSet(schema.HashString, []interface{}{"0.0.0.0/0", "::/0"}),
+		"destination_tags":               schema.NewSet(schema.HashString, []interface{}{}),
+		"destination_droplet_ids":        schema.NewSet(schema.HashInt, []interface{}{}),
+		"destination_load_balancer_uids": schema.NewSet(schema.HashString, []interface{}{}),
+	}
+	reordered := map[string]interface{}{
+		"protocol":                       "udp",
+		"port_range":                     "53",
+		"destination_addresses":          schema.NewSet(schema.HashString, []interface{}{"::/0", "0.0.0.0/0"}),
+		"destination_tags":               schema.NewSet(schema.HashString, []interface{}{}),
+		"destination_droplet_ids":        schema.NewSet(schema.HashInt, []interface{}{}),
+		"destination_load_balancer_uids": schema.NewSet(schema.HashString, []interface{}{}),
+	}
+
+	if resourceDigitalOceanFirewallOutboundRuleHash(base) != resourceDigitalOceanFirewallOutboundRuleHash(reordered) {
+		t.Error("expected outbound rule hash to be independent of set element ordering")
+	}
+}
+
+func TestAccDigitalOceanFirewall_Basic(t *testing.T) {
+	var firewall godo.Firewall
+	name := fmt.Sprintf("firewall-%s", acctest.RandString(10))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDigitalOceanFirewallDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckDigitalOceanFirewallConfigBasic(name),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDigitalOceanFirewallExists("digitalocean_firewall.foobar", &firewall),
+					resource.TestCheckResourceAttr("digitalocean_firewall.foobar", "name", name),
+					resource.TestCheckResourceAttr("digitalocean_firewall.foobar", "inbound_rule.#", "1"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckDigitalOceanFirewallDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*Config).Client
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "digitalocean_firewall" {
+			continue
+		}
+
+		_, _, err := client.Firewalls.Get(context.Background(), rs.Primary.ID)
+		if err == nil {
+			return fmt.Errorf("firewall %s still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckDigitalOceanFirewallExists(resourceName string, firewall *godo.Firewall) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("not found: %s", resourceName)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("no firewall id is set")
+		}
+
+		client := testAccProvider.Meta().(*Config).Client
+
+		found, _, err := client.Firewalls.Get(context.Background(), rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		*firewall = *found
+		return nil
+	}
+}
+
+func testAccCheckDigitalOceanFirewallConfigBasic(name string) string {
+	return fmt.Sprintf(`
+resource "digitalocean_firewall" "foobar" {
+  name = "%s"
+
+  inbound_rule {
+    protocol         = "tcp"
+    port_range       = "22"
+    source_addresses = ["0.0.0.0/0", "::/0"]
+  }
+}
+`, name)
+}